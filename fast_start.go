@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// processVideoForFastStart remuxes the video at path so its moov atom is
+// moved to the front of the file, writing the result to path + ".processing"
+// and returning that path. Players
+// can then start playback before the whole file has downloaded. No frames
+// are re-encoded, so this is fast even for large files.
+func processVideoForFastStart(path string) (string, error) {
+	outputPath := path + ".processing"
+
+	cmd := exec.Command("ffmpeg", "-i", path, "-c", "copy", "-movflags", "faststart", "-f", "mp4", outputPath)
+	if err := cmd.Run(); err != nil {
+		os.Remove(outputPath)
+		return "", fmt.Errorf("couldn't process video for fast start: %w", err)
+	}
+
+	return outputPath, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// handlerUploadProgress streams Server-Sent Events for an in-progress
+// video upload so the frontend can render a progress bar. It has nothing
+// to send once the upload isn't running, so it simply blocks until either
+// an event arrives, the upload finishes (100%), or the client disconnects.
+func (cfg *apiConfig) handlerUploadProgress(w http.ResponseWriter, r *http.Request) {
+	videoIDString := r.PathValue("videoID")
+	videoID, err := uuid.Parse(videoIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid ID", err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, http.StatusInternalServerError, "Streaming unsupported", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	ch := cfg.progress.subscribe(videoID)
+	defer cfg.progress.unsubscribe(videoID, ch)
+
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+			if event.Percent >= 100 {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
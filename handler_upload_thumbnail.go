@@ -4,11 +4,8 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
-	"io"
 	"mime"
 	"net/http"
-	"os"
-	"path/filepath"
 
 	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
 	"github.com/google/uuid"
@@ -41,7 +38,6 @@ func (cfg *apiConfig) handlerUploadThumbnail(w http.ResponseWriter, r *http.Requ
 
 	fmt.Println("uploading thumbnail for video", videoID, "by user", userID)
 
-	// TODO: implement the upload here
 	const maxMemory = 10 << 20
 	if err = r.ParseMultipartForm(maxMemory); err != nil {
 		respondWithError(w, http.StatusBadRequest, "Unable to parse multipart form", err)
@@ -88,23 +84,14 @@ func (cfg *apiConfig) handlerUploadThumbnail(w http.ResponseWriter, r *http.Requ
 	rand.Read(b)
 	key := base64.RawURLEncoding.EncodeToString(b)
 	fileName := fmt.Sprintf("%s%s", key, fileExtension)
-	filePath := filepath.Join(cfg.assetsRoot, fileName)
-	thumbnailFile, err := os.Create(filePath)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error creating thumbnail file", err)
-	}
 
-	defer thumbnailFile.Close()
-
-	_, err = io.Copy(thumbnailFile, file)
+	thumbnailURL, err := cfg.thumbnailStore.PutObject(r.Context(), fileName, file, mediaType)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error writing content into file", err)
+		respondWithError(w, http.StatusInternalServerError, "Error storing thumbnail", err)
 		return
 	}
 
-	dataUrl := fmt.Sprintf("http://localhost:%s/assets/%s", cfg.port, fileName)
-
-	videoMetadata.ThumbnailURL = &dataUrl
+	videoMetadata.ThumbnailURL = &thumbnailURL
 
 	if err = cfg.db.UpdateVideo(videoMetadata); err != nil {
 		respondWithError(w, http.StatusBadRequest, "Error updating video metadata", err)
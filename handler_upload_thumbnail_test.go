@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/database"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/filestore"
+	"github.com/google/uuid"
+)
+
+// TestHandlerUploadThumbnail exercises handlerUploadThumbnail end to end
+// through an httptest request, using MockFileStore and a throwaway JSON
+// database so the test needs neither AWS nor the real on-disk asset
+// directory.
+func TestHandlerUploadThumbnail(t *testing.T) {
+	const jwtSecret = "test-secret"
+
+	db, err := database.NewClient(filepath.Join(t.TempDir(), "db.json"))
+	if err != nil {
+		t.Fatalf("database.NewClient: %v", err)
+	}
+
+	userID := uuid.New()
+	video, err := db.CreateVideo(database.Video{UserID: userID})
+	if err != nil {
+		t.Fatalf("CreateVideo: %v", err)
+	}
+
+	token, err := auth.MakeJWT(userID, jwtSecret, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT: %v", err)
+	}
+
+	cfg := &apiConfig{
+		db:             db,
+		jwtSecret:      jwtSecret,
+		thumbnailStore: filestore.NewMockFileStore(),
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	partHeader := textproto.MIMEHeader{}
+	partHeader.Set("Content-Disposition", `form-data; name="thumbnail"; filename="thumb.png"`)
+	partHeader.Set("Content-Type", "image/png")
+	part, err := writer.CreatePart(partHeader)
+	if err != nil {
+		t.Fatalf("CreatePart: %v", err)
+	}
+	if _, err := part.Write([]byte("not a real png, just bytes")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/thumbnail_upload/"+video.ID.String(), &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.SetPathValue("videoID", video.ID.String())
+
+	rec := httptest.NewRecorder()
+	cfg.handlerUploadThumbnail(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	updated, err := db.GetVideo(video.ID)
+	if err != nil {
+		t.Fatalf("GetVideo: %v", err)
+	}
+	if updated.ThumbnailURL == nil || !strings.HasPrefix(*updated.ThumbnailURL, "mock://") {
+		t.Fatalf("expected ThumbnailURL to be set by MockFileStore, got %+v", updated.ThumbnailURL)
+	}
+}
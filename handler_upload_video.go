@@ -2,20 +2,16 @@ package main
 
 import (
 	"bytes"
-	"crypto/rand"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"math"
 	"mime"
 	"net/http"
 	"os"
 	"os/exec"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
 	"github.com/google/uuid"
 )
@@ -73,84 +69,58 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	tempFile, err := os.CreateTemp("", "tubely-upload.mp4")
+	// Save the raw upload to a stable path (not a temp file that gets
+	// cleaned up when the request ends) and hand it off to the job queue.
+	// Probing, transcoding, thumbnailing, and the S3 upload all happen on
+	// a worker goroutine, so this handler returns as soon as the bytes
+	// are safely on disk instead of blocking on ffmpeg/ffprobe/S3.
+	rawFile, err := os.CreateTemp("", "tubely-upload-*.mp4")
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't create temp file", err)
 		return
 	}
+	defer rawFile.Close()
 
-	defer os.Remove(tempFile.Name())
-	defer tempFile.Close()
-
-	_, err = io.Copy(tempFile, file)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't write temp file", err)
-		return
-	}
-
-	if _, err = tempFile.Seek(0, io.SeekStart); err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't reset temp file's file pointer", err)
-		return
-	}
-
-	aspectRatio, err := getVideoAspectRatio(tempFile.Name())
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't get video's aspect ratio", err)
-		return
-	}
-
-	var videoType string
-
-	switch aspectRatio {
-	case "16:9":
-		videoType = "landscape"
-	case "9:16":
-		videoType = "portrait"
-	default:
-		videoType = "other"
-	}
-
-	b := make([]byte, 32)
-	if _, err = rand.Read(b); err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't generate video key", err)
-		return
+	// Publish progress to GET /api/videos/{videoID}/upload-progress while
+	// the uploaded file is copied to rawFile. r.FormFile has already read
+	// the whole request body by this point, so these events follow that
+	// copy, not the client's own transfer. processVideoJob later publishes
+	// the S3 upload's progress under the same video ID.
+	interval := cfg.progressReportInterval
+	if interval <= 0 {
+		interval = defaultProgressReportInterval
 	}
+	tracked := newProgressReader(file, videoID, cfg.progress, header.Size, interval)
 
-	key := videoType + "/" + hex.EncodeToString(b) + ".mp4"
-
-	params := s3.PutObjectInput{
-		Bucket:      aws.String(cfg.s3Bucket),
-		Key:         aws.String(key),
-		Body:        tempFile,
-		ContentType: aws.String(mediaType),
-	}
-
-	_, err = cfg.s3Client.PutObject(r.Context(), &params)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't put object into s3", err)
+	if _, err = io.Copy(rawFile, tracked); err != nil {
+		os.Remove(rawFile.Name())
+		respondWithError(w, http.StatusInternalServerError, "Couldn't write temp file", err)
 		return
 	}
 
-	videoURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", cfg.s3Bucket, cfg.s3Region, key)
-	videoMetadata.VideoURL = &videoURL
-	if err = cfg.db.UpdateVideo(videoMetadata); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Error updating video metadata", err)
-		return
-	}
+	job := cfg.jobQueue.Enqueue(videoID, rawFile.Name())
 
-	respondWithJSON(w, http.StatusOK, videoMetadata)
+	respondWithJSON(w, http.StatusAccepted, struct {
+		JobID   uuid.UUID `json:"jobId"`
+		VideoID uuid.UUID `json:"videoId"`
+		State   string    `json:"state"`
+	}{
+		JobID:   job.ID,
+		VideoID: job.VideoID,
+		State:   string(job.State),
+	})
 }
 
+// aspectRatioTolerance is how far off of exactly 16/9 (or 9/16) a video's
+// width/height ratio may be and still be classified as landscape/portrait.
+const aspectRatioTolerance = 0.05
+
 func getVideoAspectRatio(filePath string) (string, error) {
 	var b bytes.Buffer
 	cmd := exec.Command("ffprobe", "-v", "error", "-print_format", "json", "-show_streams", filePath)
 	cmd.Stdout = &b
-	var exitErr *exec.ExitError
-	err := cmd.Run()
-	log.Printf("Command finished with error: %v", err)
-	if errors.As(err, &exitErr) {
-		log.Fatal("Couldn't run command")
-		return "", err
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("couldn't run ffprobe: %w", err)
 	}
 
 	type StreamInfo struct {
@@ -165,14 +135,33 @@ func getVideoAspectRatio(filePath string) (string, error) {
 	file := Response{}
 
 	if err := json.Unmarshal(b.Bytes(), &file); err != nil {
-		log.Fatal("Couldn't unmarshal")
-		return "", err
+		return "", fmt.Errorf("couldn't unmarshal ffprobe output: %w", err)
 	}
 
-	if file.Streams[0].Width/file.Streams[0].Height == 16/9 {
+	if len(file.Streams) == 0 {
+		return "", errors.New("no video streams found")
+	}
+
+	return classifyAspectRatio(file.Streams[0].Width, file.Streams[0].Height)
+}
+
+// classifyAspectRatio buckets a width/height pair into "16:9", "9:16", or
+// "other", using float division and a tolerance so resolutions that are
+// close to but not exactly 16:9 (e.g. 1920x1080 vs. 1918x1080) still
+// classify correctly.
+func classifyAspectRatio(width, height int) (string, error) {
+	if width == 0 || height == 0 {
+		return "", fmt.Errorf("invalid video dimensions: %dx%d", width, height)
+	}
+
+	ratio := float64(width) / float64(height)
+
+	switch {
+	case math.Abs(ratio-16.0/9.0) <= aspectRatioTolerance:
 		return "16:9", nil
-	} else if file.Streams[0].Height/file.Streams[0].Width == 16/9 {
+	case math.Abs(ratio-9.0/16.0) <= aspectRatioTolerance:
 		return "9:16", nil
+	default:
+		return "other", nil
 	}
-	return "other", nil
 }
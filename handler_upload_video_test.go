@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestClassifyAspectRatio(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		want   string
+	}{
+		{"1920x1080 landscape", 1920, 1080, "16:9"},
+		{"1080x1920 portrait", 1080, 1920, "9:16"},
+		{"1280x720 landscape", 1280, 720, "16:9"},
+		{"720x1280 portrait", 720, 1280, "9:16"},
+		{"640x480 other", 640, 480, "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := classifyAspectRatio(tt.width, tt.height)
+			if err != nil {
+				t.Fatalf("classifyAspectRatio(%d, %d): %v", tt.width, tt.height, err)
+			}
+			if got != tt.want {
+				t.Errorf("classifyAspectRatio(%d, %d) = %q, want %q", tt.width, tt.height, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyAspectRatioInvalidDimensions(t *testing.T) {
+	if _, err := classifyAspectRatio(0, 1080); err == nil {
+		t.Fatal("expected error for zero width")
+	}
+	if _, err := classifyAspectRatio(1920, 0); err == nil {
+		t.Fatal("expected error for zero height")
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// handlerGetVideoJob reports how far a background upload has gotten, so
+// the frontend can poll instead of holding the upload request open.
+func (cfg *apiConfig) handlerGetVideoJob(w http.ResponseWriter, r *http.Request) {
+	videoIDString := r.PathValue("videoID")
+	videoID, err := uuid.Parse(videoIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid ID", err)
+		return
+	}
+
+	job, ok := cfg.jobs.Get(videoID)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "No upload job found for this video", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct {
+		JobID   uuid.UUID `json:"jobId"`
+		VideoID uuid.UUID `json:"videoId"`
+		State   string    `json:"state"`
+		Error   string    `json:"error,omitempty"`
+	}{
+		JobID:   job.ID,
+		VideoID: job.VideoID,
+		State:   string(job.State),
+		Error:   job.Error,
+	})
+}
@@ -0,0 +1,20 @@
+// Package filestore abstracts where uploaded assets (thumbnails, videos)
+// are written to, so handlers don't need to know whether they're talking
+// to the local disk, S3, or a test double.
+package filestore
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// FileStore persists objects under a key and hands back a URL clients can
+// use to fetch them again. Implementations decide what "URL" means:
+// a local path served over HTTP, a public S3 URL, or a presigned one.
+type FileStore interface {
+	PutObject(ctx context.Context, key string, body io.Reader, contentType string) (string, error)
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	DeleteObject(ctx context.Context, key string) error
+	PresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
@@ -0,0 +1,61 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalFileStore writes objects to disk under root and serves them back
+// through baseURL, e.g. "http://localhost:8091/assets".
+type LocalFileStore struct {
+	root    string
+	baseURL string
+}
+
+func NewLocalFileStore(root, baseURL string) *LocalFileStore {
+	return &LocalFileStore{root: root, baseURL: baseURL}
+}
+
+func (s *LocalFileStore) PutObject(ctx context.Context, key string, body io.Reader, contentType string) (string, error) {
+	path := filepath.Join(s.root, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("couldn't create directory for %s: %w", path, err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("couldn't create file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, body); err != nil {
+		return "", fmt.Errorf("couldn't write file %s: %w", path, err)
+	}
+
+	return fmt.Sprintf("%s/%s", s.baseURL, key), nil
+}
+
+func (s *LocalFileStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(s.root, key))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open file %s: %w", key, err)
+	}
+	return f, nil
+}
+
+func (s *LocalFileStore) DeleteObject(ctx context.Context, key string) error {
+	if err := os.Remove(filepath.Join(s.root, key)); err != nil {
+		return fmt.Errorf("couldn't delete file %s: %w", key, err)
+	}
+	return nil
+}
+
+// PresignedURL has no meaningful expiry on disk, so it just returns the
+// same URL PutObject would have handed back.
+func (s *LocalFileStore) PresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return fmt.Sprintf("%s/%s", s.baseURL, key), nil
+}
@@ -0,0 +1,57 @@
+package filestore
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLocalFileStorePutObjectCreatesNestedDirs(t *testing.T) {
+	root := t.TempDir()
+	store := NewLocalFileStore(root, "http://localhost:8091/assets")
+	ctx := context.Background()
+
+	url, err := store.PutObject(ctx, "landscape/abc123.mp4", strings.NewReader("hello"), "video/mp4")
+	if err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+
+	wantURL := "http://localhost:8091/assets/landscape/abc123.mp4"
+	if url != wantURL {
+		t.Errorf("url = %q, want %q", url, wantURL)
+	}
+
+	rc, err := store.GetObject(ctx, "landscape/abc123.mp4")
+	if err != nil {
+		t.Fatalf("GetObject: %v", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("data = %q, want %q", data, "hello")
+	}
+}
+
+func TestLocalFileStoreDeleteObject(t *testing.T) {
+	root := t.TempDir()
+	store := NewLocalFileStore(root, "http://localhost:8091/assets")
+	ctx := context.Background()
+
+	if _, err := store.PutObject(ctx, "thumbnails/abc.jpg", strings.NewReader("hello"), "image/jpeg"); err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+
+	if err := store.DeleteObject(ctx, "thumbnails/abc.jpg"); err != nil {
+		t.Fatalf("DeleteObject: %v", err)
+	}
+
+	if _, err := os.Stat(root + "/thumbnails/abc.jpg"); !os.IsNotExist(err) {
+		t.Fatalf("expected file to be removed, stat err = %v", err)
+	}
+}
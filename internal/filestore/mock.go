@@ -0,0 +1,58 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// MockFileStore keeps objects in memory so handler tests don't need a
+// disk or AWS credentials.
+type MockFileStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func NewMockFileStore() *MockFileStore {
+	return &MockFileStore{objects: make(map[string][]byte)}
+}
+
+func (s *MockFileStore) PutObject(ctx context.Context, key string, body io.Reader, contentType string) (string, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.objects[key] = data
+	s.mu.Unlock()
+
+	return fmt.Sprintf("mock://%s", key), nil
+}
+
+func (s *MockFileStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	data, ok := s.objects[key]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no such object: %s", key)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *MockFileStore) DeleteObject(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.objects[key]; !ok {
+		return fmt.Errorf("no such object: %s", key)
+	}
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *MockFileStore) PresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return fmt.Sprintf("mock://%s?expires=%s", key, expiry), nil
+}
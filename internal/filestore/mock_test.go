@@ -0,0 +1,34 @@
+package filestore
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMockFileStorePutGetDelete(t *testing.T) {
+	store := NewMockFileStore()
+	ctx := context.Background()
+
+	url, err := store.PutObject(ctx, "thumbnails/abc.jpg", strings.NewReader("hello"), "image/jpeg")
+	if err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+	if url == "" {
+		t.Fatal("expected a non-empty URL")
+	}
+
+	rc, err := store.GetObject(ctx, "thumbnails/abc.jpg")
+	if err != nil {
+		t.Fatalf("GetObject: %v", err)
+	}
+	defer rc.Close()
+
+	if err := store.DeleteObject(ctx, "thumbnails/abc.jpg"); err != nil {
+		t.Fatalf("DeleteObject: %v", err)
+	}
+
+	if _, err := store.GetObject(ctx, "thumbnails/abc.jpg"); err == nil {
+		t.Fatal("expected error getting deleted object")
+	}
+}
@@ -0,0 +1,82 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3FileStore wraps the AWS SDK v2 client and presign client for a single
+// bucket/region pair.
+type S3FileStore struct {
+	client        *s3.Client
+	presignClient *s3.PresignClient
+	uploader      *manager.Uploader
+	bucket        string
+	region        string
+}
+
+func NewS3FileStore(client *s3.Client, presignClient *s3.PresignClient, bucket, region string) *S3FileStore {
+	return &S3FileStore{
+		client:        client,
+		presignClient: presignClient,
+		uploader:      manager.NewUploader(client),
+		bucket:        bucket,
+		region:        region,
+	}
+}
+
+// PutObject streams body to S3 as a multipart upload: the uploader reads
+// it in parts rather than buffering the whole object in memory first, and
+// it aborts the multipart upload automatically if any part fails.
+func (s *S3FileStore) PutObject(ctx context.Context, key string, body io.Reader, contentType string) (string, error) {
+	_, err := s.uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("couldn't put object %s into s3: %w", key, err)
+	}
+
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, key), nil
+}
+
+func (s *S3FileStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get object %s from s3: %w", key, err)
+	}
+	return out.Body, nil
+}
+
+func (s *S3FileStore) DeleteObject(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't delete object %s from s3: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3FileStore) PresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	req, err := s.presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", fmt.Errorf("couldn't presign object %s: %w", key, err)
+	}
+	return req.URL, nil
+}
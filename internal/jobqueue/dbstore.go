@@ -0,0 +1,210 @@
+package jobqueue
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jobsSchema creates the jobs table DBStore reads and writes. NewDBStore
+// runs it on the *sql.DB it is given, so callers need no separate
+// migration step.
+const jobsSchema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	id               TEXT PRIMARY KEY,
+	video_id         TEXT NOT NULL UNIQUE,
+	raw_path         TEXT NOT NULL,
+	state            TEXT NOT NULL,
+	error            TEXT NOT NULL DEFAULT '',
+	stage_timestamps TEXT NOT NULL
+);
+`
+
+// DBStore is a Store backed by a jobs table, so pending/probing jobs are
+// still on disk if the process is restarted mid-upload. It works with any
+// database/sql driver the caller has already registered and connected
+// that accepts its SQL: the queries use "?" placeholders and an
+// ON CONFLICT upsert, so drivers with another placeholder style (such as
+// Postgres's $1) need those queries adjusted.
+type DBStore struct {
+	db *sql.DB
+
+	// jobLocks holds one mutex per videoID, serializing Transition's
+	// read-modify-write of that job's row so two concurrent transitions
+	// on the same job can't race and silently drop one of the updates.
+	// It's keyed per job rather than store-wide so workers processing
+	// different videos never block on each other's DB round-trips.
+	jobLocks sync.Map // uuid.UUID -> *sync.Mutex
+}
+
+// NewDBStore wraps db and ensures the jobs table exists.
+func NewDBStore(db *sql.DB) (*DBStore, error) {
+	if _, err := db.Exec(jobsSchema); err != nil {
+		return nil, fmt.Errorf("couldn't create jobs table: %w", err)
+	}
+	return &DBStore{db: db}, nil
+}
+
+// lockFor returns the mutex guarding videoID's row, creating one on first
+// use.
+func (s *DBStore) lockFor(videoID uuid.UUID) *sync.Mutex {
+	mu, _ := s.jobLocks.LoadOrStore(videoID, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
+
+// Create upserts the job row for videoID: a re-upload/retry of a video
+// that already has a job (in any state) replaces it outright, matching
+// MemStore's "s.jobs[videoID] = job" overwrite semantics rather than
+// failing on video_id's UNIQUE constraint and leaving the stale row (and
+// its now-deleted raw_path) in place for the worker to choke on.
+func (s *DBStore) Create(videoID uuid.UUID, rawPath string) *VideoJob {
+	lock := s.lockFor(videoID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	job := &VideoJob{
+		ID:      uuid.New(),
+		VideoID: videoID,
+		RawPath: rawPath,
+		State:   StatePending,
+		StageTimestamps: map[State]time.Time{
+			StatePending: time.Now(),
+		},
+	}
+
+	stamps, _ := json.Marshal(job.StageTimestamps)
+	_, err := s.db.Exec(
+		`INSERT INTO jobs (id, video_id, raw_path, state, error, stage_timestamps) VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(video_id) DO UPDATE SET
+			id = excluded.id,
+			raw_path = excluded.raw_path,
+			state = excluded.state,
+			error = excluded.error,
+			stage_timestamps = excluded.stage_timestamps`,
+		job.ID, job.VideoID, job.RawPath, string(job.State), job.Error, string(stamps),
+	)
+	if err != nil {
+		// Create has no error return in the Store interface (it mirrors
+		// MemStore, which can't fail either). Since the row was never
+		// written, a later Get/Transition for videoID will just find no
+		// job rather than this error, so log it here instead of losing it.
+		log.Printf("jobqueue: couldn't persist job for video %s: %v", videoID, err)
+	}
+
+	return job
+}
+
+func (s *DBStore) Transition(videoID, jobID uuid.UUID, state State, errMsg string) error {
+	lock := s.lockFor(videoID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	job, ok := s.get(videoID)
+	if !ok {
+		return fmt.Errorf("no job for video %s", videoID)
+	}
+	if job.ID != jobID {
+		// videoID's row already belongs to a newer job (a re-upload/retry
+		// called Create while this one was still running); writing our
+		// state over it would corrupt the newer job's history, so bail
+		// out instead of touching the row at all.
+		return ErrSuperseded
+	}
+
+	// A job that's reached a terminal state will never be transitioned
+	// again, so drop its lock once we're done with it rather than
+	// growing jobLocks for the life of the process.
+	if state == StateComplete || state == StateFailed {
+		defer s.jobLocks.Delete(videoID)
+	}
+
+	job.State = state
+	job.StageTimestamps[state] = time.Now()
+	if state == StateFailed {
+		job.Error = errMsg
+	}
+
+	stamps, err := json.Marshal(job.StageTimestamps)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal stage timestamps: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		`UPDATE jobs SET state = ?, error = ?, stage_timestamps = ? WHERE video_id = ? AND id = ?`,
+		string(job.State), job.Error, string(stamps), videoID, jobID,
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't update job for video %s: %w", videoID, err)
+	}
+
+	return nil
+}
+
+// Get reads videoID's job straight from the DB; *sql.DB's connection pool
+// already makes this safe to call concurrently with Transition.
+func (s *DBStore) Get(videoID uuid.UUID) (VideoJob, bool) {
+	return s.get(videoID)
+}
+
+// get is the shared implementation behind Get and Transition's
+// read-modify-write.
+func (s *DBStore) get(videoID uuid.UUID) (VideoJob, bool) {
+	row := s.db.QueryRow(
+		`SELECT id, video_id, raw_path, state, error, stage_timestamps FROM jobs WHERE video_id = ?`,
+		videoID,
+	)
+
+	job, err := scanJob(row.Scan)
+	if err != nil {
+		return VideoJob{}, false
+	}
+	return job, true
+}
+
+func (s *DBStore) Resumable() []VideoJob {
+	rows, err := s.db.Query(
+		`SELECT id, video_id, raw_path, state, error, stage_timestamps FROM jobs WHERE state IN (?, ?)`,
+		string(StatePending), string(StateProbing),
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var jobs []VideoJob
+	for rows.Next() {
+		job, err := scanJob(rows.Scan)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// scanJob decodes a jobs row via scan, which is either a *sql.Row's or
+// *sql.Rows's Scan method.
+func scanJob(scan func(dest ...any) error) (VideoJob, error) {
+	var (
+		job      VideoJob
+		state    string
+		stampsJS string
+	)
+
+	if err := scan(&job.ID, &job.VideoID, &job.RawPath, &state, &job.Error, &stampsJS); err != nil {
+		return VideoJob{}, err
+	}
+	job.State = State(state)
+
+	job.StageTimestamps = make(map[State]time.Time)
+	if err := json.Unmarshal([]byte(stampsJS), &job.StageTimestamps); err != nil {
+		return VideoJob{}, fmt.Errorf("couldn't unmarshal stage timestamps: %w", err)
+	}
+
+	return job, nil
+}
@@ -0,0 +1,180 @@
+package jobqueue
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+)
+
+// newTestDBStore opens an in-memory sqlite database and wraps it in a
+// DBStore, so tests exercise the real SQL in jobsSchema/Create/Transition
+// without touching disk.
+func newTestDBStore(t *testing.T) *DBStore {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store, err := NewDBStore(db)
+	if err != nil {
+		t.Fatalf("NewDBStore: %v", err)
+	}
+	return store
+}
+
+func TestDBStoreCreateAndGet(t *testing.T) {
+	store := newTestDBStore(t)
+	videoID := uuid.New()
+
+	created := store.Create(videoID, "/tmp/raw.mp4")
+
+	got, ok := store.Get(videoID)
+	if !ok {
+		t.Fatal("expected job to be found after Create")
+	}
+	if got.ID != created.ID || got.RawPath != "/tmp/raw.mp4" || got.State != StatePending {
+		t.Fatalf("got %+v, want ID=%s RawPath=/tmp/raw.mp4 State=pending", got, created.ID)
+	}
+}
+
+// TestDBStoreCreateUpsertsExistingJob matches MemStore's overwrite
+// semantics: re-uploading/retrying a video replaces its job row instead
+// of failing on video_id's UNIQUE constraint.
+func TestDBStoreCreateUpsertsExistingJob(t *testing.T) {
+	store := newTestDBStore(t)
+	videoID := uuid.New()
+
+	first := store.Create(videoID, "/tmp/raw-1.mp4")
+	second := store.Create(videoID, "/tmp/raw-2.mp4")
+
+	got, ok := store.Get(videoID)
+	if !ok {
+		t.Fatal("expected job to be found after second Create")
+	}
+	if got.ID != second.ID || got.ID == first.ID {
+		t.Fatalf("expected the second job (%s) to win, got %s", second.ID, got.ID)
+	}
+	if got.RawPath != "/tmp/raw-2.mp4" {
+		t.Fatalf("got RawPath %q, want /tmp/raw-2.mp4", got.RawPath)
+	}
+}
+
+func TestDBStoreTransitionUpdatesStateAndError(t *testing.T) {
+	store := newTestDBStore(t)
+	videoID := uuid.New()
+	job := store.Create(videoID, "/tmp/raw.mp4")
+
+	if err := store.Transition(videoID, job.ID, StateProbing, ""); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+
+	got, _ := store.Get(videoID)
+	if got.State != StateProbing {
+		t.Fatalf("got state %q, want %q", got.State, StateProbing)
+	}
+	if _, ok := got.StageTimestamps[StateProbing]; !ok {
+		t.Fatal("expected StageTimestamps to record the probing transition")
+	}
+
+	if err := store.Transition(videoID, job.ID, StateFailed, "ffmpeg exploded"); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+
+	got, _ = store.Get(videoID)
+	if got.State != StateFailed || got.Error != "ffmpeg exploded" {
+		t.Fatalf("got %+v, want State=failed Error=%q", got, "ffmpeg exploded")
+	}
+}
+
+func TestDBStoreTransitionUnknownVideo(t *testing.T) {
+	store := newTestDBStore(t)
+	if err := store.Transition(uuid.New(), uuid.New(), StateProbing, ""); err == nil {
+		t.Fatal("expected an error transitioning a job that doesn't exist")
+	}
+}
+
+// TestDBStoreTransitionSupersededJobReturnsErrSuperseded proves that a
+// Transition carrying a stale jobID never touches videoID's row: once
+// Create replaces the job (a re-upload/retry while the first run is still
+// in flight), the abandoned run's Transition calls must fail instead of
+// clobbering the newer job's state.
+func TestDBStoreTransitionSupersededJobReturnsErrSuperseded(t *testing.T) {
+	store := newTestDBStore(t)
+	videoID := uuid.New()
+
+	stale := store.Create(videoID, "/tmp/raw-1.mp4")
+	current := store.Create(videoID, "/tmp/raw-2.mp4")
+
+	err := store.Transition(videoID, stale.ID, StateProbing, "")
+	if !errors.Is(err, ErrSuperseded) {
+		t.Fatalf("Transition with stale jobID: got %v, want ErrSuperseded", err)
+	}
+
+	got, ok := store.Get(videoID)
+	if !ok {
+		t.Fatal("expected job to still exist")
+	}
+	if got.ID != current.ID || got.State != StatePending {
+		t.Fatalf("expected the current job to be untouched, got %+v", got)
+	}
+}
+
+func TestDBStoreResumable(t *testing.T) {
+	store := newTestDBStore(t)
+
+	pending := uuid.New()
+	store.Create(pending, "/tmp/pending.mp4")
+
+	done := uuid.New()
+	doneJob := store.Create(done, "/tmp/done.mp4")
+	if err := store.Transition(done, doneJob.ID, StateComplete, ""); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+
+	resumable := store.Resumable()
+	if len(resumable) != 1 || resumable[0].VideoID != pending {
+		t.Fatalf("expected only the pending job to be resumable, got %+v", resumable)
+	}
+}
+
+// TestDBStoreConcurrentTransitionsDoNotLoseUpdates drives many concurrent
+// Transitions for the same job through the per-job lock in lockFor; if
+// that locking is ever removed, concurrent read-modify-writes can drop a
+// stage timestamp, which surfaces here as a missing state or a race
+// detector failure.
+func TestDBStoreConcurrentTransitionsDoNotLoseUpdates(t *testing.T) {
+	store := newTestDBStore(t)
+	videoID := uuid.New()
+	job := store.Create(videoID, "/tmp/raw.mp4")
+
+	states := []State{StateProbing, StateTranscoding, StateUploading}
+
+	var wg sync.WaitGroup
+	for _, state := range states {
+		wg.Add(1)
+		go func(state State) {
+			defer wg.Done()
+			if err := store.Transition(videoID, job.ID, state, ""); err != nil {
+				t.Errorf("Transition(%s): %v", state, err)
+			}
+		}(state)
+	}
+	wg.Wait()
+
+	got, ok := store.Get(videoID)
+	if !ok {
+		t.Fatal("expected job to still exist")
+	}
+	for _, state := range states {
+		if _, ok := got.StageTimestamps[state]; !ok {
+			t.Errorf("expected StageTimestamps to record %s, got %+v", state, got.StageTimestamps)
+		}
+	}
+}
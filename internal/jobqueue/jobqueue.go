@@ -0,0 +1,65 @@
+// Package jobqueue decouples the video upload request from the slow
+// ffprobe/ffmpeg/S3 pipeline that turns a raw upload into a playable
+// video. A VideoJob moves through a small state machine as a worker pool
+// processes it; clients poll GET /api/videos/{videoID}/job instead of
+// holding the upload connection open.
+package jobqueue
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrSuperseded is returned by Transition when videoID's job has moved on
+// to a newer jobID (a re-upload or retry replaced it via Create) since the
+// caller last read it. A pipeline that sees this should abandon its run
+// rather than keep writing state for a job nothing is polling anymore.
+var ErrSuperseded = errors.New("jobqueue: job superseded by a newer one")
+
+// State is one step of a VideoJob's state machine. Jobs only move
+// forward; a failure at any step moves straight to Failed.
+type State string
+
+const (
+	StatePending     State = "pending"
+	StateProbing     State = "probing"
+	StateTranscoding State = "transcoding"
+	StateUploading   State = "uploading"
+	StateComplete    State = "complete"
+	StateFailed      State = "failed"
+)
+
+// VideoJob tracks one raw upload as it's processed into a stored video.
+// StageTimestamps records when the job entered each state it has passed
+// through, so progress and stalls are both visible.
+type VideoJob struct {
+	ID              uuid.UUID
+	VideoID         uuid.UUID
+	RawPath         string
+	State           State
+	Error           string
+	StageTimestamps map[State]time.Time
+}
+
+// Store persists VideoJobs and their state transitions. Implementations
+// must be safe for concurrent use. MemStore is a non-durable reference
+// implementation used in tests; DBStore is backed by a real jobs table so
+// pending/probing jobs survive a process restart.
+type Store interface {
+	// Create records a new job for videoID in StatePending and returns it.
+	Create(videoID uuid.UUID, rawPath string) *VideoJob
+	// Transition moves the job for videoID to state, stamping the time it
+	// entered that state, but only if jobID still matches the job
+	// currently stored for videoID. Transitioning to StateFailed also
+	// records errMsg. If videoID's job has since been replaced by a newer
+	// Create, Transition leaves that newer job untouched and returns
+	// ErrSuperseded instead of clobbering it.
+	Transition(videoID, jobID uuid.UUID, state State, errMsg string) error
+	// Get returns a copy of the job for videoID.
+	Get(videoID uuid.UUID) (VideoJob, bool)
+	// Resumable returns jobs left in StatePending or StateProbing, which is
+	// what a process should re-enqueue on boot if it crashed mid-upload.
+	Resumable() []VideoJob
+}
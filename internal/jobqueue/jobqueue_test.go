@@ -0,0 +1,199 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestWorkerPoolProcessesJobToCompletion(t *testing.T) {
+	store := NewMemStore()
+	queue := NewQueue(store)
+
+	pool := NewWorkerPool(1, queue, store, func(ctx context.Context, job VideoJob) error {
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.Start(ctx)
+
+	videoID := uuid.New()
+	queue.Enqueue(videoID, "/tmp/raw.mp4")
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		job, ok := store.Get(videoID)
+		if ok && job.State == StateComplete {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job never completed, last state: %+v", job)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWorkerPoolRecordsFailure(t *testing.T) {
+	store := NewMemStore()
+	queue := NewQueue(store)
+
+	wantErr := errors.New("ffmpeg exploded")
+	pool := NewWorkerPool(1, queue, store, func(ctx context.Context, job VideoJob) error {
+		return wantErr
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.Start(ctx)
+
+	videoID := uuid.New()
+	queue.Enqueue(videoID, "/tmp/raw.mp4")
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		job, ok := store.Get(videoID)
+		if ok && job.State == StateFailed {
+			if job.Error != wantErr.Error() {
+				t.Fatalf("got error %q, want %q", job.Error, wantErr.Error())
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job never failed, last state: %+v", job)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWorkerPoolSkipsSupersededJob(t *testing.T) {
+	store := NewMemStore()
+	queue := NewQueue(store)
+
+	var mu sync.Mutex
+	var processed []uuid.UUID
+	pool := NewWorkerPool(1, queue, store, func(ctx context.Context, job VideoJob) error {
+		mu.Lock()
+		processed = append(processed, job.ID)
+		mu.Unlock()
+		return nil
+	})
+
+	// Enqueue twice for the same video before any worker is running, as a
+	// double submit or quick retry would: Create replaces the first job
+	// row outright, so the queue ends up with one stale entry and one
+	// current one for the same videoID.
+	videoID := uuid.New()
+	queue.Enqueue(videoID, "/tmp/raw-1.mp4")
+	second := queue.Enqueue(videoID, "/tmp/raw-2.mp4")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.Start(ctx)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		job, ok := store.Get(videoID)
+		if ok && job.State == StateComplete {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job never completed, last state: %+v", job)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(processed) != 1 || processed[0] != second.ID {
+		t.Fatalf("expected only the current job %s to be processed, got %v", second.ID, processed)
+	}
+}
+
+// TestWorkerPoolAbandonsSupersededJobMidRun starts a job, supersedes it
+// with a retry while it's still mid-pipeline (not merely still queued,
+// the way TestWorkerPoolSkipsSupersededJob does), and asserts that only
+// the new job's result survives: the stale run's Transition call must
+// come back as ErrSuperseded instead of overwriting the newer job's
+// state, the same checkpoint processVideoJob takes before its own
+// Transition/UpdateVideo calls.
+func TestWorkerPoolAbandonsSupersededJobMidRun(t *testing.T) {
+	store := NewMemStore()
+	queue := NewQueue(store)
+
+	staleStarted := make(chan struct{})
+	staleContinue := make(chan struct{})
+
+	process := func(ctx context.Context, job VideoJob) error {
+		if job.RawPath != "/tmp/stale.mp4" {
+			return nil
+		}
+		close(staleStarted)
+		<-staleContinue
+		// Mirrors processVideoJob: check back in with the store before
+		// writing further state, rather than writing blindly.
+		return store.Transition(job.VideoID, job.ID, StateUploading, "")
+	}
+
+	pool := NewWorkerPool(2, queue, store, process)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.Start(ctx)
+
+	videoID := uuid.New()
+	queue.Enqueue(videoID, "/tmp/stale.mp4")
+
+	select {
+	case <-staleStarted:
+	case <-time.After(time.Second):
+		t.Fatal("stale job never started")
+	}
+
+	current := queue.Enqueue(videoID, "/tmp/current.mp4")
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		job, ok := store.Get(videoID)
+		if ok && job.State == StateComplete {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("current job never completed, last state: %+v", job)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	close(staleContinue)
+	// Give the stale run a moment to attempt (and fail) its write.
+	time.Sleep(20 * time.Millisecond)
+
+	got, ok := store.Get(videoID)
+	if !ok {
+		t.Fatal("expected job to still exist")
+	}
+	if got.ID != current.ID || got.State != StateComplete {
+		t.Fatalf("expected the current job's result to survive untouched, got %+v", got)
+	}
+}
+
+func TestStoreResumable(t *testing.T) {
+	store := NewMemStore()
+	videoID := uuid.New()
+	job := store.Create(videoID, "/tmp/raw.mp4")
+
+	resumable := store.Resumable()
+	if len(resumable) != 1 || resumable[0].VideoID != videoID {
+		t.Fatalf("expected pending job to be resumable, got %+v", resumable)
+	}
+
+	if err := store.Transition(videoID, job.ID, StateComplete, ""); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	if resumable := store.Resumable(); len(resumable) != 0 {
+		t.Fatalf("expected no resumable jobs after completion, got %+v", resumable)
+	}
+}
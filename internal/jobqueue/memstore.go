@@ -0,0 +1,84 @@
+package jobqueue
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemStore is a non-durable Store backed by a plain map. It's useful for
+// tests and local development, but a restart loses every job it holds;
+// use DBStore when jobs need to survive one.
+type MemStore struct {
+	mu   sync.Mutex
+	jobs map[uuid.UUID]*VideoJob
+}
+
+func NewMemStore() *MemStore {
+	return &MemStore{jobs: make(map[uuid.UUID]*VideoJob)}
+}
+
+func (s *MemStore) Create(videoID uuid.UUID, rawPath string) *VideoJob {
+	job := &VideoJob{
+		ID:      uuid.New(),
+		VideoID: videoID,
+		RawPath: rawPath,
+		State:   StatePending,
+		StageTimestamps: map[State]time.Time{
+			StatePending: time.Now(),
+		},
+	}
+
+	s.mu.Lock()
+	s.jobs[videoID] = job
+	s.mu.Unlock()
+
+	return job
+}
+
+func (s *MemStore) Transition(videoID, jobID uuid.UUID, state State, errMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[videoID]
+	if !ok {
+		return fmt.Errorf("no job for video %s", videoID)
+	}
+	if job.ID != jobID {
+		return ErrSuperseded
+	}
+
+	job.State = state
+	job.StageTimestamps[state] = time.Now()
+	if state == StateFailed {
+		job.Error = errMsg
+	}
+
+	return nil
+}
+
+func (s *MemStore) Get(videoID uuid.UUID) (VideoJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[videoID]
+	if !ok {
+		return VideoJob{}, false
+	}
+	return *job, true
+}
+
+func (s *MemStore) Resumable() []VideoJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs []VideoJob
+	for _, job := range s.jobs {
+		if job.State == StatePending || job.State == StateProbing {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs
+}
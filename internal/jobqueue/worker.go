@@ -0,0 +1,160 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ProcessFunc runs the actual probing/transcoding/upload pipeline for a
+// job. It's supplied by the caller (main's apiConfig) so this package
+// doesn't need to know about ffmpeg, S3, or the database.
+type ProcessFunc func(ctx context.Context, job VideoJob) error
+
+// queuedJob is what Queue hands a worker: the job to run and the specific
+// job ID it was enqueued for. Store.Create replaces a video's job outright
+// on re-upload/retry, so a queue entry can be superseded by a newer one for
+// the same videoID before a worker gets to it; processOne compares jobID
+// against the store's current row and skips the entry if they don't match,
+// rather than double-processing the same RawPath.
+type queuedJob struct {
+	videoID uuid.UUID
+	jobID   uuid.UUID
+}
+
+// Queue is an unbounded queue of jobs waiting to be processed. Enqueue and
+// Resume never block: entries are buffered in pending and handed to
+// workers one at a time through out. A busy or empty worker pool can never
+// make handlerUploadVideo (which calls Enqueue synchronously) stall, no
+// matter how deep the backlog gets.
+type Queue struct {
+	store Store
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []queuedJob
+	out     chan queuedJob
+}
+
+// NewQueue wires a Queue to the Store that holds job state and starts the
+// goroutine that feeds pending entries to out.
+func NewQueue(store Store) *Queue {
+	q := &Queue{store: store, out: make(chan queuedJob)}
+	q.cond = sync.NewCond(&q.mu)
+	go q.forward()
+	return q
+}
+
+// forward moves entries from pending to out one at a time, blocking on out
+// (not on pending) when no worker is ready, so Enqueue/Resume never wait on
+// a worker.
+func (q *Queue) forward() {
+	for {
+		q.mu.Lock()
+		for len(q.pending) == 0 {
+			q.cond.Wait()
+		}
+		job := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		q.out <- job
+	}
+}
+
+// push appends job to pending and wakes forward.
+func (q *Queue) push(job queuedJob) {
+	q.mu.Lock()
+	q.pending = append(q.pending, job)
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+// Enqueue creates a job for videoID and schedules it for processing.
+func (q *Queue) Enqueue(videoID uuid.UUID, rawPath string) *VideoJob {
+	job := q.store.Create(videoID, rawPath)
+	q.push(queuedJob{videoID: videoID, jobID: job.ID})
+	return job
+}
+
+// Resume re-schedules any job left in StatePending or StateProbing, for
+// use on process boot after an unclean shutdown.
+func (q *Queue) Resume() {
+	for _, job := range q.store.Resumable() {
+		q.push(queuedJob{videoID: job.VideoID, jobID: job.ID})
+	}
+}
+
+// WorkerPool pulls jobs off a Queue and runs process against the
+// corresponding job, updating Store as it moves through states.
+type WorkerPool struct {
+	queue   *Queue
+	store   Store
+	process ProcessFunc
+	size    int
+}
+
+func NewWorkerPool(size int, queue *Queue, store Store, process ProcessFunc) *WorkerPool {
+	return &WorkerPool{queue: queue, store: store, process: process, size: size}
+}
+
+// Start launches size worker goroutines that run until ctx is canceled,
+// then re-enqueues any job left pending/probing from before this process
+// started (so it survives restarting mid-upload).
+func (p *WorkerPool) Start(ctx context.Context) {
+	for i := 0; i < p.size; i++ {
+		go p.run(ctx)
+	}
+
+	p.queue.Resume()
+}
+
+func (p *WorkerPool) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-p.queue.out:
+			p.processOne(ctx, job)
+		}
+	}
+}
+
+func (p *WorkerPool) processOne(ctx context.Context, qj queuedJob) {
+	job, ok := p.store.Get(qj.videoID)
+	if !ok {
+		log.Printf("jobqueue: no job found for video %s", qj.videoID)
+		return
+	}
+
+	if job.ID != qj.jobID {
+		log.Printf("jobqueue: skipping superseded job for video %s (queued %s, current %s)", qj.videoID, qj.jobID, job.ID)
+		return
+	}
+
+	// Resume can queue a job that Enqueue already queued; once one entry
+	// has finished it, the other is skipped instead of run again.
+	if job.State == StateComplete || job.State == StateFailed {
+		log.Printf("jobqueue: skipping already finished job for video %s", qj.videoID)
+		return
+	}
+
+	if err := p.process(ctx, job); err != nil {
+		if errors.Is(err, ErrSuperseded) {
+			log.Printf("jobqueue: job for video %s was superseded mid-run, abandoning it", qj.videoID)
+			return
+		}
+		log.Printf("jobqueue: job for video %s failed: %v", qj.videoID, err)
+		if tErr := p.store.Transition(qj.videoID, qj.jobID, StateFailed, err.Error()); tErr != nil && !errors.Is(tErr, ErrSuperseded) {
+			log.Printf("jobqueue: couldn't record failure for video %s: %v", qj.videoID, tErr)
+		}
+		return
+	}
+
+	if err := p.store.Transition(qj.videoID, qj.jobID, StateComplete, ""); err != nil && !errors.Is(err, ErrSuperseded) {
+		log.Printf("jobqueue: couldn't record completion for video %s: %v", qj.videoID, err)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/database"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/filestore"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/jobqueue"
+	_ "modernc.org/sqlite"
+)
+
+// apiConfig holds everything a handler needs to serve a request: the
+// video metadata store, auth secret, and the backend-agnostic FileStores
+// thumbnails and videos are written through.
+type apiConfig struct {
+	db        *database.Client
+	jwtSecret string
+
+	assetsRoot string
+	port       string
+
+	s3Bucket string
+	s3Region string
+	s3Client *s3.Client
+
+	// thumbnailStore and videoStore are selected at startup based on the
+	// STORAGE_BACKEND environment variable, so handlers never know whether
+	// they're talking to the local disk or S3.
+	thumbnailStore filestore.FileStore
+	videoStore     filestore.FileStore
+	urlExpiry      time.Duration
+
+	jobQueue               *jobqueue.Queue
+	jobs                   jobqueue.Store
+	progress               *progressBroker
+	progressReportInterval time.Duration
+}
+
+func main() {
+	assetsRoot := envOrDefault("ASSETS_ROOT", "./assets")
+	if err := os.MkdirAll(assetsRoot, 0o755); err != nil {
+		log.Fatalf("couldn't create assets root: %v", err)
+	}
+
+	port := envOrDefault("PORT", "8091")
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		log.Fatal("JWT_SECRET must be set")
+	}
+
+	db, err := database.NewClient(envOrDefault("DB_PATH", "./db.json"))
+	if err != nil {
+		log.Fatalf("couldn't connect to database: %v", err)
+	}
+
+	cfg := &apiConfig{
+		db:                     db,
+		jwtSecret:              jwtSecret,
+		assetsRoot:             assetsRoot,
+		port:                   port,
+		s3Bucket:               os.Getenv("S3_BUCKET"),
+		s3Region:               os.Getenv("S3_REGION"),
+		urlExpiry:              defaultURLExpiry,
+		progress:               newProgressBroker(),
+		progressReportInterval: defaultProgressReportInterval,
+	}
+
+	baseURL := "http://localhost:" + port + "/assets"
+	switch backend := envOrDefault("STORAGE_BACKEND", "local"); backend {
+	case "local":
+		local := filestore.NewLocalFileStore(assetsRoot, baseURL)
+		cfg.thumbnailStore, cfg.videoStore = local, local
+	case "s3":
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(cfg.s3Region))
+		if err != nil {
+			log.Fatalf("couldn't load AWS config: %v", err)
+		}
+		cfg.s3Client = s3.NewFromConfig(awsCfg)
+		presignClient := s3.NewPresignClient(cfg.s3Client)
+		s3Store := filestore.NewS3FileStore(cfg.s3Client, presignClient, cfg.s3Bucket, cfg.s3Region)
+		cfg.thumbnailStore, cfg.videoStore = s3Store, s3Store
+	default:
+		log.Fatalf("unknown STORAGE_BACKEND %q", backend)
+	}
+
+	jobsDB, err := sql.Open("sqlite", envOrDefault("JOBS_DB_PATH", "./jobs.db"))
+	if err != nil {
+		log.Fatalf("couldn't open jobs database: %v", err)
+	}
+	jobStore, err := jobqueue.NewDBStore(jobsDB)
+	if err != nil {
+		log.Fatalf("couldn't set up job store: %v", err)
+	}
+	cfg.jobs = jobStore
+
+	queue := jobqueue.NewQueue(jobStore)
+	cfg.jobQueue = queue
+
+	poolSize := 4
+	if v := os.Getenv("WORKER_POOL_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			log.Fatalf("invalid WORKER_POOL_SIZE %q: %v", v, err)
+		}
+		poolSize = n
+	}
+	pool := jobqueue.NewWorkerPool(poolSize, queue, jobStore, cfg.processVideoJob)
+	pool.Start(context.Background())
+
+	mux := http.NewServeMux()
+	mux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.Dir(assetsRoot))))
+	mux.HandleFunc("POST /api/thumbnail_upload/{videoID}", cfg.handlerUploadThumbnail)
+	mux.HandleFunc("POST /api/video_upload/{videoID}", cfg.handlerUploadVideo)
+	mux.HandleFunc("GET /api/videos/{videoID}", cfg.handlerGetVideo)
+	mux.HandleFunc("GET /api/videos", cfg.handlerGetVideos)
+	mux.HandleFunc("GET /api/videos/{videoID}/upload-progress", cfg.handlerUploadProgress)
+	mux.HandleFunc("GET /api/videos/{videoID}/job", cfg.handlerGetVideoJob)
+
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: mux,
+	}
+
+	log.Printf("serving on port %s", port)
+	log.Fatal(srv.ListenAndServe())
+}
+
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/database"
+)
+
+// defaultURLExpiry is used when the server isn't configured with an
+// explicit urlExpiry.
+const defaultURLExpiry = time.Hour
+
+// splitBucketKey turns the "bucket,key" value stored in VideoURL back into
+// its two parts.
+func splitBucketKey(bucketAndKey string) (bucket, key string, err error) {
+	parts := strings.SplitN(bucketAndKey, ",", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid bucket,key value: %q", bucketAndKey)
+	}
+	return parts[0], parts[1], nil
+}
+
+// dbVideoToSignedVideo rewrites video.VideoURL, which is stored as a
+// "bucket,key" pair, into a freshly presigned GET URL so the value
+// returned to clients always has time left before it expires.
+func (cfg *apiConfig) dbVideoToSignedVideo(video database.Video) (database.Video, error) {
+	if video.VideoURL == nil || *video.VideoURL == "" {
+		return video, nil
+	}
+
+	_, key, err := splitBucketKey(*video.VideoURL)
+	if err != nil {
+		return video, err
+	}
+
+	expiry := cfg.urlExpiry
+	if expiry <= 0 {
+		expiry = defaultURLExpiry
+	}
+
+	signedURL, err := cfg.videoStore.PresignedURL(context.Background(), key, expiry)
+	if err != nil {
+		return video, fmt.Errorf("couldn't presign video url: %w", err)
+	}
+
+	video.VideoURL = &signedURL
+	return video, nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/database"
+)
+
+// spyFileStore is a filestore.FileStore double that records how it was
+// called, so tests can assert dbVideoToSignedVideo re-signs on every call
+// instead of reusing a cached URL.
+type spyFileStore struct {
+	presignCalls int
+	lastExpiry   time.Duration
+}
+
+func (s *spyFileStore) PutObject(ctx context.Context, key string, body io.Reader, contentType string) (string, error) {
+	return "", nil
+}
+
+func (s *spyFileStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *spyFileStore) DeleteObject(ctx context.Context, key string) error {
+	return nil
+}
+
+func (s *spyFileStore) PresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	s.presignCalls++
+	s.lastExpiry = expiry
+	return fmt.Sprintf("https://signed.example/%s?call=%d&expiry=%s", key, s.presignCalls, expiry), nil
+}
+
+func TestSplitBucketKey(t *testing.T) {
+	bucket, key, err := splitBucketKey("my-bucket,landscape/abc123.mp4")
+	if err != nil {
+		t.Fatalf("splitBucketKey: %v", err)
+	}
+	if bucket != "my-bucket" || key != "landscape/abc123.mp4" {
+		t.Fatalf("got bucket=%q key=%q", bucket, key)
+	}
+
+	if _, _, err := splitBucketKey("not-a-pair"); err == nil {
+		t.Fatal("expected error for malformed bucket,key value")
+	}
+}
+
+func TestDbVideoToSignedVideoUsesConfiguredExpiry(t *testing.T) {
+	store := &spyFileStore{}
+	cfg := &apiConfig{videoStore: store, s3Bucket: "my-bucket", urlExpiry: time.Minute}
+
+	bucketAndKey := "my-bucket,landscape/abc123.mp4"
+	video := database.Video{VideoURL: &bucketAndKey}
+
+	if _, err := cfg.dbVideoToSignedVideo(video); err != nil {
+		t.Fatalf("dbVideoToSignedVideo: %v", err)
+	}
+
+	if store.lastExpiry != time.Minute {
+		t.Fatalf("got expiry %s, want %s", store.lastExpiry, time.Minute)
+	}
+}
+
+func TestDbVideoToSignedVideoDefaultsExpiryWhenUnset(t *testing.T) {
+	store := &spyFileStore{}
+	cfg := &apiConfig{videoStore: store, s3Bucket: "my-bucket"}
+
+	bucketAndKey := "my-bucket,landscape/abc123.mp4"
+	video := database.Video{VideoURL: &bucketAndKey}
+
+	if _, err := cfg.dbVideoToSignedVideo(video); err != nil {
+		t.Fatalf("dbVideoToSignedVideo: %v", err)
+	}
+
+	if store.lastExpiry != defaultURLExpiry {
+		t.Fatalf("got expiry %s, want default %s", store.lastExpiry, defaultURLExpiry)
+	}
+}
+
+// TestDbVideoToSignedVideoResignsOnEveryCall proves that a URL handed out
+// from an earlier call is never reused on a later read: every call to
+// dbVideoToSignedVideo asks the store for a brand new presigned URL, so a
+// client polling GetVideo never sees an expired link.
+func TestDbVideoToSignedVideoResignsOnEveryCall(t *testing.T) {
+	store := &spyFileStore{}
+	cfg := &apiConfig{videoStore: store, s3Bucket: "my-bucket", urlExpiry: time.Minute}
+
+	bucketAndKey := "my-bucket,landscape/abc123.mp4"
+	video := database.Video{VideoURL: &bucketAndKey}
+
+	first, err := cfg.dbVideoToSignedVideo(video)
+	if err != nil {
+		t.Fatalf("dbVideoToSignedVideo: %v", err)
+	}
+
+	second, err := cfg.dbVideoToSignedVideo(video)
+	if err != nil {
+		t.Fatalf("dbVideoToSignedVideo: %v", err)
+	}
+
+	if store.presignCalls != 2 {
+		t.Fatalf("expected a fresh presign per call, got %d calls", store.presignCalls)
+	}
+	if *first.VideoURL == *second.VideoURL {
+		t.Fatal("expected each call to produce a distinct, freshly signed URL")
+	}
+}
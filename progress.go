@@ -0,0 +1,118 @@
+package main
+
+import (
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultProgressReportInterval bounds how often a progressReader
+// publishes an event, so a fast upload doesn't flood subscribers with one
+// event per chunk. Used when the server isn't configured with an
+// explicit progressReportInterval.
+const defaultProgressReportInterval = 500 * time.Millisecond
+
+// progressEvent is pushed to upload-progress subscribers as they happen.
+type progressEvent struct {
+	BytesRead  int64   `json:"bytesRead"`
+	TotalBytes int64   `json:"totalBytes"`
+	Percent    float64 `json:"percent"`
+}
+
+// progressBroker fans out progressEvents for in-flight uploads to any
+// number of SSE subscribers, keyed by video ID.
+type progressBroker struct {
+	mu          sync.Mutex
+	subscribers map[uuid.UUID][]chan progressEvent
+}
+
+func newProgressBroker() *progressBroker {
+	return &progressBroker{subscribers: make(map[uuid.UUID][]chan progressEvent)}
+}
+
+func (b *progressBroker) subscribe(videoID uuid.UUID) chan progressEvent {
+	ch := make(chan progressEvent, 16)
+	b.mu.Lock()
+	b.subscribers[videoID] = append(b.subscribers[videoID], ch)
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *progressBroker) unsubscribe(videoID uuid.UUID, ch chan progressEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs := b.subscribers[videoID]
+	for i, c := range subs {
+		if c == ch {
+			b.subscribers[videoID] = append(subs[:i], subs[i+1:]...)
+			close(ch)
+			break
+		}
+	}
+	if len(b.subscribers[videoID]) == 0 {
+		delete(b.subscribers, videoID)
+	}
+}
+
+func (b *progressBroker) publish(videoID uuid.UUID, event progressEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subscribers[videoID] {
+		select {
+		case ch <- event:
+		default:
+			// Subscriber isn't keeping up; drop the event rather than
+			// block the upload on a slow SSE client.
+		}
+	}
+}
+
+// progressReader wraps an io.Reader and publishes a progressEvent to a
+// broker at most once per interval, plus a final event when the wrapped
+// reader reports io.EOF, so clients can render an upload bar without
+// polling.
+type progressReader struct {
+	r          io.Reader
+	videoID    uuid.UUID
+	broker     *progressBroker
+	totalBytes int64
+	bytesRead  int64
+	interval   time.Duration
+	lastSent   time.Time
+}
+
+func newProgressReader(r io.Reader, videoID uuid.UUID, broker *progressBroker, totalBytes int64, interval time.Duration) *progressReader {
+	return &progressReader{
+		r:          r,
+		videoID:    videoID,
+		broker:     broker,
+		totalBytes: totalBytes,
+		interval:   interval,
+	}
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	if n > 0 {
+		p.bytesRead += int64(n)
+	}
+
+	if (n > 0 && time.Since(p.lastSent) >= p.interval) || err == io.EOF {
+		p.lastSent = time.Now()
+		var percent float64
+		if p.totalBytes > 0 {
+			percent = float64(p.bytesRead) / float64(p.totalBytes) * 100
+		}
+		p.broker.publish(p.videoID, progressEvent{
+			BytesRead:  p.bytesRead,
+			TotalBytes: p.totalBytes,
+			Percent:    percent,
+		})
+		log.Printf("upload progress for %s: %.1f%%", p.videoID, percent)
+	}
+
+	return n, err
+}
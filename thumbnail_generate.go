@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/google/uuid"
+)
+
+// thumbnailWidth and thumbnailHeight match the 16:9 preview size used
+// elsewhere in the UI.
+const (
+	thumbnailWidth  = 177
+	thumbnailHeight = 100
+)
+
+// generateThumbnail extracts a single keyframe from videoPath with ffmpeg
+// and writes it as a JPEG to a new temp file, whose path is returned. The
+// caller is responsible for removing it once it's been uploaded.
+func generateThumbnail(videoPath string) (string, error) {
+	thumbFile, err := os.CreateTemp("", "tubely-thumbnail-*.jpg")
+	if err != nil {
+		return "", fmt.Errorf("couldn't create temp thumbnail file: %w", err)
+	}
+	thumbPath := thumbFile.Name()
+	thumbFile.Close()
+
+	scale := fmt.Sprintf("scale=%d:%d", thumbnailWidth, thumbnailHeight)
+	cmd := exec.Command(
+		"ffmpeg", "-y",
+		"-ss", "00:00:01",
+		"-i", videoPath,
+		"-vframes", "1",
+		"-vf", scale,
+		thumbPath,
+	)
+	if err := cmd.Run(); err != nil {
+		os.Remove(thumbPath)
+		return "", fmt.Errorf("ffmpeg thumbnail extraction failed: %w", err)
+	}
+
+	return thumbPath, nil
+}
+
+// thumbnailForVideo generates a thumbnail for videoPath and uploads it
+// under a key derived from videoID, returning its URL along with the
+// width/height it was scaled to. Thumbnail generation is a nice-to-have,
+// not a requirement for a successful video upload, so callers should log
+// the error and move on rather than fail the request.
+//
+// Known gap: the width/height return values are not persisted anywhere.
+// database.Video has no columns for them, and adding those columns is a
+// DB migration out of scope for this package, so callers currently only
+// log the dimensions. Persisting them alongside the thumbnail record, as
+// originally requested, needs that migration landed first.
+func (cfg *apiConfig) thumbnailForVideo(ctx context.Context, videoID uuid.UUID, videoPath string) (url string, width, height int, err error) {
+	thumbPath, err := generateThumbnail(videoPath)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	defer os.Remove(thumbPath)
+
+	thumbFile, err := os.Open(thumbPath)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("couldn't open generated thumbnail: %w", err)
+	}
+	defer thumbFile.Close()
+
+	key := fmt.Sprintf("%s.jpg", videoID)
+	thumbnailURL, err := cfg.thumbnailStore.PutObject(ctx, key, thumbFile, "image/jpeg")
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("couldn't store generated thumbnail: %w", err)
+	}
+
+	return thumbnailURL, thumbnailWidth, thumbnailHeight, nil
+}
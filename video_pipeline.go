@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/jobqueue"
+)
+
+// processVideoJob runs the ffprobe/ffmpeg/S3 pipeline for a single
+// uploaded file. It's the ProcessFunc handed to the jobqueue.WorkerPool,
+// and it's also what handlerUploadVideo used to do inline on the request
+// goroutine before uploads moved to the background.
+func (cfg *apiConfig) processVideoJob(ctx context.Context, job jobqueue.VideoJob) error {
+	defer os.Remove(job.RawPath)
+
+	if err := cfg.jobs.Transition(job.VideoID, job.ID, jobqueue.StateProbing, ""); err != nil {
+		return err
+	}
+
+	videoMetadata, err := cfg.db.GetVideo(job.VideoID)
+	if err != nil {
+		return fmt.Errorf("couldn't get video metadata: %w", err)
+	}
+
+	aspectRatio, err := getVideoAspectRatio(job.RawPath)
+	if err != nil {
+		return fmt.Errorf("couldn't get video's aspect ratio: %w", err)
+	}
+
+	var videoType string
+	switch aspectRatio {
+	case "16:9":
+		videoType = "landscape"
+	case "9:16":
+		videoType = "portrait"
+	default:
+		videoType = "other"
+	}
+
+	if err := cfg.jobs.Transition(job.VideoID, job.ID, jobqueue.StateTranscoding, ""); err != nil {
+		return err
+	}
+
+	fastStartPath, err := processVideoForFastStart(job.RawPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fastStartPath)
+
+	// database.Video has no columns for the thumbnail's dimensions, so
+	// only the URL is persisted; width/height are logged for visibility
+	// until a migration adds them.
+	if thumbnailURL, width, height, thumbErr := cfg.thumbnailForVideo(ctx, job.VideoID, fastStartPath); thumbErr != nil {
+		log.Printf("couldn't generate thumbnail for video %s: %v", job.VideoID, thumbErr)
+	} else {
+		videoMetadata.ThumbnailURL = &thumbnailURL
+		log.Printf("generated %dx%d thumbnail for video %s", width, height, job.VideoID)
+	}
+
+	if err := cfg.jobs.Transition(job.VideoID, job.ID, jobqueue.StateUploading, ""); err != nil {
+		return err
+	}
+
+	fastStartFile, err := os.Open(fastStartPath)
+	if err != nil {
+		return fmt.Errorf("couldn't open processed video: %w", err)
+	}
+	defer fastStartFile.Close()
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("couldn't generate video key: %w", err)
+	}
+	key := videoType + "/" + hex.EncodeToString(b) + ".mp4"
+
+	var totalBytes int64
+	if info, statErr := fastStartFile.Stat(); statErr == nil {
+		totalBytes = info.Size()
+	}
+	interval := cfg.progressReportInterval
+	if interval <= 0 {
+		interval = defaultProgressReportInterval
+	}
+	tracked := newProgressReader(fastStartFile, job.VideoID, cfg.progress, totalBytes, interval)
+
+	if _, err := cfg.videoStore.PutObject(ctx, key, tracked, "video/mp4"); err != nil {
+		return fmt.Errorf("couldn't store video: %w", err)
+	}
+
+	bucketAndKey := cfg.s3Bucket + "," + key
+	videoMetadata.VideoURL = &bucketAndKey
+
+	// The S3 upload above has no checkpoint of its own, so re-check
+	// against the job store before writing video metadata: if a
+	// re-upload/retry superseded this job while PutObject was running,
+	// videoMetadata here is for the abandoned run and must not overwrite
+	// whatever the newer job has already written (or is about to).
+	if current, ok := cfg.jobs.Get(job.VideoID); !ok || current.ID != job.ID {
+		return jobqueue.ErrSuperseded
+	}
+
+	if err := cfg.db.UpdateVideo(videoMetadata); err != nil {
+		return fmt.Errorf("error updating video metadata: %w", err)
+	}
+
+	return nil
+}